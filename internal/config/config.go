@@ -0,0 +1,52 @@
+// Package config loads the aggregator's feed list from a YAML config file.
+package config
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/dntiontk/rss-feed-aggregator/internal/feed"
+	"github.com/dntiontk/rss-feed-aggregator/internal/output"
+	"gopkg.in/yaml.v3"
+)
+
+// defaultTimeout is used for any feed that doesn't set its own timeout.
+const defaultTimeout = 30 * time.Second
+
+// Config is the top-level shape of the aggregator's config file.
+type Config struct {
+	Feeds []Feed `yaml:"feeds"`
+}
+
+// Feed is a configured feed along with the outputs its updates should be
+// published to.
+type Feed struct {
+	feed.Feed `yaml:",inline"`
+
+	// Outputs lists where this feed's updates are published. A feed with
+	// no outputs configured falls back to stdout.
+	Outputs []output.Config `yaml:"outputs"`
+}
+
+// Load reads and parses the config file at path, filling in defaults for
+// any feed that omits them.
+func Load(path string) (*Config, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read config file: %v", err)
+	}
+
+	var cfg Config
+	if err := yaml.Unmarshal(b, &cfg); err != nil {
+		return nil, fmt.Errorf("unable to parse config file: %v", err)
+	}
+
+	for i, f := range cfg.Feeds {
+		if f.Timeout == 0 {
+			cfg.Feeds[i].Timeout = defaultTimeout
+		}
+	}
+
+	return &cfg, nil
+}
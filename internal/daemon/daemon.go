@@ -0,0 +1,132 @@
+// Package daemon keeps the aggregator running as a long-lived process,
+// polling each configured feed on its own interval instead of relying on
+// an external scheduler like cron.
+package daemon
+
+import (
+	"context"
+	"log"
+	"math/rand"
+	"sync"
+	"time"
+
+	"github.com/dntiontk/rss-feed-aggregator/internal/config"
+	"github.com/dntiontk/rss-feed-aggregator/internal/feed"
+	"github.com/dntiontk/rss-feed-aggregator/internal/output"
+)
+
+// defaultInterval is used for any feed that sets neither an explicit
+// interval nor advertises a Cache-Control max-age/ttl.
+const defaultInterval = 15 * time.Minute
+
+// jitterFraction is the maximum fraction of an interval added as jitter,
+// so that feeds sharing an interval don't all poll in lockstep.
+const jitterFraction = 0.2
+
+// Daemon polls a set of configured feeds, each on its own schedule, until
+// its context is cancelled.
+type Daemon struct {
+	Pool       *feed.ClientPool
+	Feeds      []config.Feed
+	MinRefresh time.Duration
+	Metrics    *Metrics
+}
+
+// New returns a Daemon ready to poll feeds.
+func New(pool *feed.ClientPool, feeds []config.Feed, minRefresh time.Duration) *Daemon {
+	return &Daemon{
+		Pool:       pool,
+		Feeds:      feeds,
+		MinRefresh: minRefresh,
+		Metrics:    NewMetrics(),
+	}
+}
+
+// Run polls every configured feed on its own schedule until ctx is
+// cancelled, then waits for in-flight polls to finish before returning.
+func (d *Daemon) Run(ctx context.Context) {
+	var wg sync.WaitGroup
+	for _, f := range d.Feeds {
+		wg.Add(1)
+		go func(f config.Feed) {
+			defer wg.Done()
+			d.pollLoop(ctx, f)
+		}(f)
+	}
+	wg.Wait()
+}
+
+// pollLoop repeatedly polls f until ctx is cancelled, sleeping between
+// polls for an interval derived from f's config or the server's advertised
+// freshness, jittered to avoid a thundering herd.
+func (d *Daemon) pollLoop(ctx context.Context, f config.Feed) {
+	if !sleep(ctx, jitter(d.interval(f))) {
+		return
+	}
+	for {
+		d.poll(ctx, f)
+		if !sleep(ctx, jitter(d.interval(f))) {
+			return
+		}
+	}
+}
+
+// interval returns how long to wait before polling f again: its own
+// configured Interval if set, else the last poll's server-advertised
+// freshness window, else defaultInterval.
+func (d *Daemon) interval(f config.Feed) time.Duration {
+	if f.Interval > 0 {
+		return f.Interval
+	}
+	return feed.NextPollInterval(f.Path, defaultInterval)
+}
+
+// poll fetches f once, records the outcome in d.Metrics, and publishes any
+// updated items to f's configured outputs.
+func (d *Daemon) poll(ctx context.Context, f config.Feed) {
+	client, err := d.Pool.Get(f.Feed)
+	if err != nil {
+		log.Printf("%s: %v", f.Name, err)
+		d.Metrics.RecordError(f.Name)
+		return
+	}
+
+	fetchCtx, cancel := feed.FetchContext(ctx, f.Timeout)
+	defer cancel()
+
+	items, err := feed.GetUpdates(fetchCtx, client, f.Feed, d.MinRefresh)
+	if err != nil {
+		log.Printf("%s: %v", f.Name, err)
+		d.Metrics.RecordError(f.Name)
+		return
+	}
+
+	d.Metrics.RecordSuccess(f.Name, len(items))
+	if len(items) == 0 {
+		return
+	}
+
+	output.PublishAll(ctx, client, f.Name, f.Outputs, items)
+}
+
+// jitter adds up to jitterFraction of d as random slack, so feeds sharing
+// the same interval don't all poll at once.
+func jitter(d time.Duration) time.Duration {
+	if d <= 0 {
+		return d
+	}
+	return d + time.Duration(rand.Int63n(int64(float64(d)*jitterFraction)+1))
+}
+
+// sleep waits for d or until ctx is cancelled, reporting whether it slept
+// the full duration (false means the caller should stop).
+func sleep(ctx context.Context, d time.Duration) bool {
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-t.C:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
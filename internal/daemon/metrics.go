@@ -0,0 +1,102 @@
+package daemon
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"sync"
+	"time"
+)
+
+// feedMetrics is what's tracked per feed for observability.
+type feedMetrics struct {
+	lastFetch  time.Time
+	lastStatus string
+	itemCount  int
+	errorCount int
+}
+
+// Metrics holds per-feed counters for the daemon's /metrics endpoint.
+type Metrics struct {
+	mu    sync.Mutex
+	feeds map[string]*feedMetrics
+}
+
+// NewMetrics returns an empty Metrics ready for use.
+func NewMetrics() *Metrics {
+	return &Metrics{feeds: make(map[string]*feedMetrics)}
+}
+
+// RecordSuccess records a successful poll of feedName that found
+// itemCount updated items.
+func (m *Metrics) RecordSuccess(feedName string, itemCount int) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	f := m.feedFor(feedName)
+	f.lastFetch = time.Now()
+	f.lastStatus = "ok"
+	f.itemCount = itemCount
+}
+
+// RecordError records a failed poll of feedName.
+func (m *Metrics) RecordError(feedName string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	f := m.feedFor(feedName)
+	f.lastFetch = time.Now()
+	f.lastStatus = "error"
+	f.errorCount++
+}
+
+func (m *Metrics) feedFor(feedName string) *feedMetrics {
+	f, ok := m.feeds[feedName]
+	if !ok {
+		f = &feedMetrics{}
+		m.feeds[feedName] = f
+	}
+	return f
+}
+
+// WriteProm writes m in Prometheus text exposition format.
+func (m *Metrics) WriteProm(w io.Writer) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	names := make([]string, 0, len(m.feeds))
+	for name := range m.feeds {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	fmt.Fprintln(w, "# HELP rss_feed_last_fetch_timestamp_seconds Unix time of the last poll attempt.")
+	fmt.Fprintln(w, "# TYPE rss_feed_last_fetch_timestamp_seconds gauge")
+	for _, name := range names {
+		f := m.feeds[name]
+		fmt.Fprintf(w, "rss_feed_last_fetch_timestamp_seconds{feed=%q} %d\n", name, f.lastFetch.Unix())
+	}
+
+	fmt.Fprintln(w, "# HELP rss_feed_last_status Status of the last poll: 1 for ok, 0 for error.")
+	fmt.Fprintln(w, "# TYPE rss_feed_last_status gauge")
+	for _, name := range names {
+		f := m.feeds[name]
+		status := 0
+		if f.lastStatus == "ok" {
+			status = 1
+		}
+		fmt.Fprintf(w, "rss_feed_last_status{feed=%q} %d\n", name, status)
+	}
+
+	fmt.Fprintln(w, "# HELP rss_feed_items_total Items found on the last successful poll.")
+	fmt.Fprintln(w, "# TYPE rss_feed_items_total gauge")
+	for _, name := range names {
+		f := m.feeds[name]
+		fmt.Fprintf(w, "rss_feed_items_total{feed=%q} %d\n", name, f.itemCount)
+	}
+
+	fmt.Fprintln(w, "# HELP rss_feed_errors_total Number of failed polls.")
+	fmt.Fprintln(w, "# TYPE rss_feed_errors_total counter")
+	for _, name := range names {
+		f := m.feeds[name]
+		fmt.Fprintf(w, "rss_feed_errors_total{feed=%q} %d\n", name, f.errorCount)
+	}
+}
@@ -0,0 +1,31 @@
+package daemon
+
+import (
+	"context"
+	"net/http"
+)
+
+// ServeMetrics starts an HTTP server on addr exposing d.Metrics at
+// /metrics in Prometheus text exposition format, shutting down cleanly
+// when ctx is cancelled.
+func (d *Daemon) ServeMetrics(ctx context.Context, addr string) error {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/metrics", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/plain; version=0.0.4")
+		d.Metrics.WriteProm(w)
+	})
+
+	srv := &http.Server{Addr: addr, Handler: mux}
+
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- srv.ListenAndServe()
+	}()
+
+	select {
+	case err := <-errCh:
+		return err
+	case <-ctx.Done():
+		return srv.Shutdown(context.Background())
+	}
+}
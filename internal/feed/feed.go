@@ -0,0 +1,220 @@
+// Package feed fetches and diffs RSS, Atom, and JSON feeds against a local
+// cache, producing the list of items that are new or changed since the
+// last run.
+package feed
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/mmcdole/gofeed"
+)
+
+// Feed describes a single feed to track: where to fetch it from, where to
+// cache it on disk, and how to talk to it over HTTP.
+type Feed struct {
+	Name    string        `yaml:"name"`
+	URL     string        `yaml:"url"`
+	Path    string        `yaml:"path"`
+	Timeout time.Duration `yaml:"timeout"`
+	NoTLS   bool          `yaml:"tls-no-verify"`
+	CACert  string        `yaml:"ca-cert"`
+
+	// Interval is how often to poll this feed in --daemon mode. Zero means
+	// the daemon derives a polling interval itself (from the server's
+	// Cache-Control max-age/ttl, falling back to a default).
+	Interval time.Duration `yaml:"interval"`
+}
+
+// defaultFetchTimeout bounds a feed fetch when the feed declares no
+// timeout of its own.
+const defaultFetchTimeout = 30 * time.Second
+
+// FetchContext derives a context bounded by timeout (or defaultFetchTimeout
+// if timeout is zero or negative) from parent, in the style of
+// feed2imap-go's per-fetch context helper. Callers should use it to bound
+// every call to GetUpdates/ParseRemoteFeed so a hung server can't block a
+// feed fetch forever.
+func FetchContext(parent context.Context, timeout time.Duration) (context.Context, context.CancelFunc) {
+	if timeout <= 0 {
+		timeout = defaultFetchTimeout
+	}
+	return context.WithTimeout(parent, timeout)
+}
+
+// GetUpdates fetches f's remote copy with client and returns the items that
+// are new or have changed since the last run, comparing against a
+// fingerprint state file rather than the cached copy's publish date so
+// that edits and malformed dates are still detected. The remote copy
+// replaces the local cache as a side effect. minRefresh suppresses the HTTP
+// call entirely when the cached copy was fetched more recently than
+// minRefresh ago. ctx bounds the HTTP request, if one is made.
+func GetUpdates(ctx context.Context, client *http.Client, f Feed, minRefresh time.Duration) ([]*gofeed.Item, error) {
+	remoteFeed, err := ParseRemoteFeed(ctx, client, f.Path, f.URL, minRefresh)
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse remote feed: %v", err)
+	}
+
+	prevState, err := loadState(f.Path)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read feed state: %v", err)
+	}
+
+	updatedItems, nextState := LookupUpdates(prevState, remoteFeed.Items)
+
+	if err := saveState(f.Path, nextState); err != nil {
+		return nil, fmt.Errorf("unable to save feed state: %v", err)
+	}
+
+	return updatedItems, nil
+}
+
+// LookupUpdates compares items against prevState and returns the items that
+// are new, whose content fingerprint changed, or whose resolved publish
+// date moved forward relative to what was last recorded. It also returns
+// the State that should be persisted for the next run.
+func LookupUpdates(prevState State, items []*gofeed.Item) ([]*gofeed.Item, State) {
+	nextState := State{Items: make(map[string]itemState, len(items))}
+	updatedItems := make([]*gofeed.Item, 0)
+
+	for _, item := range items {
+		key := itemKey(item)
+		fp := fingerprint(item)
+		pubDate, hasPubDate := resolvePubDate(item)
+
+		prev, seen := prevState.Items[key]
+		changed := !seen
+		if seen {
+			if prev.Fingerprint != fp {
+				changed = true
+			} else if hasPubDate && pubDate.After(prev.PubDate) {
+				changed = true
+			}
+		}
+		if changed {
+			updatedItems = append(updatedItems, item)
+		}
+
+		nextState.Items[key] = itemState{Fingerprint: fp, PubDate: pubDate}
+	}
+
+	return updatedItems, nextState
+}
+
+// ParseRemoteFeed fetches url with c, persists the response body to path,
+// and parses it, auto-detecting RSS, Atom, or JSON Feed. It is a
+// well-behaved HTTP client: it sends a conditional GET using the
+// ETag/Last-Modified recorded from the previous fetch, and skips the
+// request entirely (parsing the local copy instead) when the server's
+// Expires/Cache-Control says the cached copy is still fresh or when it was
+// fetched less than minRefresh ago. ctx bounds the HTTP request.
+func ParseRemoteFeed(ctx context.Context, c *http.Client, path, url string, minRefresh time.Duration) (*gofeed.Feed, error) {
+	meta, err := loadMeta(path)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read feed cache metadata: %v", err)
+	}
+
+	now := time.Now()
+	if minRefresh > 0 && now.Sub(meta.FetchedAt) < minRefresh {
+		return ParseLocalFeed(path)
+	}
+	if meta.isFresh(now) {
+		return ParseLocalFeed(path)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("unable to build request for remote feed: %v", err)
+	}
+	if meta.ETag != "" {
+		req.Header.Set("If-None-Match", meta.ETag)
+	}
+	if meta.LastModified != "" {
+		req.Header.Set("If-Modified-Since", meta.LastModified)
+	}
+
+	resp, err := c.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("unable to get remote feed: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotModified {
+		updated := metaFromResponse(resp, now)
+		if updated.ETag == "" {
+			updated.ETag = meta.ETag
+		}
+		if updated.LastModified == "" {
+			updated.LastModified = meta.LastModified
+		}
+		if err := saveMeta(path, updated); err != nil {
+			return nil, fmt.Errorf("unable to save feed cache metadata: %v", err)
+		}
+		return ParseLocalFeed(path)
+	}
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	if err := write(data, path); err != nil {
+		return nil, err
+	}
+
+	feed, err := parseFeed(bytes.NewBuffer(data))
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse remote feed: %v", err)
+	}
+
+	respMeta := metaFromResponse(resp, now)
+	if respMeta.MaxAge == 0 && respMeta.Expires.IsZero() {
+		if ttl, ok := ttlFromFeed(data, feed); ok {
+			respMeta.MaxAge = ttl
+		}
+	}
+	if err := saveMeta(path, respMeta); err != nil {
+		return nil, fmt.Errorf("unable to save feed cache metadata: %v", err)
+	}
+
+	return feed, nil
+}
+
+// ParseLocalFeed parses the cached copy of a feed at path, returning an
+// empty feed if no cache exists yet.
+func ParseLocalFeed(path string) (*gofeed.Feed, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return &gofeed.Feed{}, nil
+		}
+		return &gofeed.Feed{}, fmt.Errorf("unable to read local feed: %v", err)
+	}
+
+	feed, err := parseFeed(bytes.NewBuffer(b))
+	if err != nil {
+		return &gofeed.Feed{}, fmt.Errorf("unable to parse local feed: %v", err)
+	}
+
+	return feed, nil
+}
+
+func write(b []byte, path string) error {
+	return os.WriteFile(path, b, 0o644)
+}
+
+// parseFeed parses r as a feed, auto-detecting whether it's RSS, Atom, or
+// JSON Feed.
+func parseFeed(r io.Reader) (*gofeed.Feed, error) {
+	fp := gofeed.NewParser()
+
+	feed, err := fp.Parse(r)
+	if err != nil {
+		return nil, err
+	}
+	return feed, nil
+}
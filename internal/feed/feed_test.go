@@ -0,0 +1,59 @@
+package feed
+
+import (
+	"os"
+	"testing"
+)
+
+func TestParseFeedFormats(t *testing.T) {
+	tests := []struct {
+		path      string
+		wantTitle string
+		wantKey   string
+	}{
+		{
+			path:      "testdata/windsor.rss.xml",
+			wantTitle: "Building Permits Dataset Updated",
+			wantKey:   "opendata-building-permits-2026-07-20",
+		},
+		{
+			path:      "testdata/sample.atom.xml",
+			wantTitle: "New episode: how feed readers work",
+			wantKey:   "yt:video:abc123",
+		},
+		{
+			path:      "testdata/sample.jsonfeed.json",
+			wantTitle: "Shipping JSON Feed support",
+			wantKey:   "https://example.com/posts/1",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			f, err := os.Open(tt.path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer f.Close()
+
+			feed, err := parseFeed(f)
+			if err != nil {
+				t.Fatalf("parseFeed: %v", err)
+			}
+			if len(feed.Items) != 1 {
+				t.Fatalf("got %d items, want 1", len(feed.Items))
+			}
+
+			item := feed.Items[0]
+			if item.Title != tt.wantTitle {
+				t.Errorf("Title = %q, want %q", item.Title, tt.wantTitle)
+			}
+			if key := itemKey(item); key != tt.wantKey {
+				t.Errorf("itemKey = %q, want %q", key, tt.wantKey)
+			}
+			if _, ok := resolvePubDate(item); !ok {
+				t.Error("resolvePubDate: expected a date to be resolved")
+			}
+		})
+	}
+}
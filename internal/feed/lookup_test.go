@@ -0,0 +1,87 @@
+package feed
+
+import (
+	"testing"
+	"time"
+
+	"github.com/mmcdole/gofeed"
+)
+
+func TestLookupUpdates(t *testing.T) {
+	pubDate := time.Date(2026, 7, 20, 9, 0, 0, 0, time.UTC)
+
+	item := &gofeed.Item{
+		GUID:            "post-1",
+		Title:           "Original title",
+		Link:            "https://example.com/posts/1",
+		Description:     "Original description.",
+		Published:       "Mon, 20 Jul 2026 09:00:00 +0000",
+		PublishedParsed: &pubDate,
+	}
+
+	prevState := State{Items: map[string]itemState{}}
+	_, prevState = LookupUpdates(prevState, []*gofeed.Item{item})
+
+	t.Run("unchanged item is not returned", func(t *testing.T) {
+		updated, _ := LookupUpdates(prevState, []*gofeed.Item{item})
+		if len(updated) != 0 {
+			t.Errorf("got %d updated items, want 0", len(updated))
+		}
+	})
+
+	t.Run("description edit with identical title+PubDate is returned", func(t *testing.T) {
+		edited := *item
+		edited.Description = "Description has been corrected."
+
+		updated, _ := LookupUpdates(prevState, []*gofeed.Item{&edited})
+		if len(updated) != 1 {
+			t.Fatalf("got %d updated items, want 1", len(updated))
+		}
+		if updated[0].Description != edited.Description {
+			t.Errorf("updated item = %+v, want the edited item", updated[0])
+		}
+	})
+}
+
+func TestLookupUpdatesMalformedPubDate(t *testing.T) {
+	item := &gofeed.Item{
+		GUID:      "post-2",
+		Title:     "No parsed date",
+		Link:      "https://example.com/posts/2",
+		Published: "2026-07-20",
+	}
+
+	updated, nextState := LookupUpdates(State{Items: map[string]itemState{}}, []*gofeed.Item{item})
+	if len(updated) != 1 {
+		t.Fatalf("got %d updated items, want 1", len(updated))
+	}
+
+	got, ok := nextState.Items[itemKey(item)]
+	if !ok {
+		t.Fatal("expected item to be recorded in next state")
+	}
+	want := time.Date(2026, 7, 20, 0, 0, 0, 0, time.UTC)
+	if !got.PubDate.Equal(want) {
+		t.Errorf("PubDate = %v, want %v", got.PubDate, want)
+	}
+}
+
+func TestItemKey(t *testing.T) {
+	tests := []struct {
+		name string
+		item *gofeed.Item
+		want string
+	}{
+		{"prefers GUID", &gofeed.Item{GUID: "g", Link: "l", Title: "t"}, "g"},
+		{"falls back to Link", &gofeed.Item{Link: "l", Title: "t"}, "l"},
+		{"falls back to Title", &gofeed.Item{Title: "t"}, "t"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := itemKey(tt.item); got != tt.want {
+				t.Errorf("itemKey = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,162 @@
+package feed
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/mmcdole/gofeed"
+	"github.com/mmcdole/gofeed/rss"
+)
+
+// cacheMeta records the HTTP caching information for a feed's cached copy,
+// persisted alongside it so conditional GETs and freshness checks survive
+// across runs.
+type cacheMeta struct {
+	ETag         string        `json:"etag,omitempty"`
+	LastModified string        `json:"last_modified,omitempty"`
+	Expires      time.Time     `json:"expires,omitempty"`
+	MaxAge       time.Duration `json:"max_age,omitempty"`
+	FetchedAt    time.Time     `json:"fetched_at"`
+}
+
+// metaPath returns the sidecar path used to cache HTTP metadata for the
+// feed cached at path.
+func metaPath(path string) string {
+	return path + ".meta.json"
+}
+
+// loadMeta reads the sidecar metadata for path, returning a zero value if
+// none exists yet.
+func loadMeta(path string) (cacheMeta, error) {
+	b, err := os.ReadFile(metaPath(path))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return cacheMeta{}, nil
+		}
+		return cacheMeta{}, err
+	}
+
+	var m cacheMeta
+	if err := json.Unmarshal(b, &m); err != nil {
+		return cacheMeta{}, err
+	}
+	return m, nil
+}
+
+// saveMeta writes m to the sidecar metadata file for path.
+func saveMeta(path string, m cacheMeta) error {
+	b, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(metaPath(path), b, 0o644)
+}
+
+// freshUntil reports the time until which m's cached copy can be trusted
+// without talking to the server at all, derived from the Expires header or
+// the Cache-Control max-age captured at fetch time. The zero Time means
+// freshness is unknown and the server should be asked.
+func (m cacheMeta) freshUntil() time.Time {
+	if m.MaxAge > 0 {
+		return m.FetchedAt.Add(m.MaxAge)
+	}
+	if !m.Expires.IsZero() {
+		return m.Expires
+	}
+	return time.Time{}
+}
+
+// isFresh reports whether m's cached copy is still valid as of now.
+func (m cacheMeta) isFresh(now time.Time) bool {
+	until := m.freshUntil()
+	return !until.IsZero() && now.Before(until)
+}
+
+// NextPollInterval reports how long the daemon should wait before the next
+// poll of the feed cached at path, preferring the server's advertised
+// freshness window (Cache-Control max-age, or Expires) over fallback.
+func NextPollInterval(path string, fallback time.Duration) time.Duration {
+	m, err := loadMeta(path)
+	if err != nil {
+		return fallback
+	}
+
+	if m.MaxAge > 0 {
+		return m.MaxAge
+	}
+	if !m.Expires.IsZero() {
+		if d := time.Until(m.Expires); d > 0 {
+			return d
+		}
+	}
+	return fallback
+}
+
+// metaFromResponse builds a cacheMeta from a feed HTTP response's caching
+// headers, stamping FetchedAt as now.
+func metaFromResponse(resp *http.Response, now time.Time) cacheMeta {
+	m := cacheMeta{
+		ETag:         resp.Header.Get("ETag"),
+		LastModified: resp.Header.Get("Last-Modified"),
+		FetchedAt:    now,
+	}
+
+	if v := resp.Header.Get("Expires"); v != "" {
+		if t, err := http.ParseTime(v); err == nil {
+			m.Expires = t
+		}
+	}
+
+	if maxAge, ok := parseMaxAge(resp.Header.Get("Cache-Control")); ok {
+		m.MaxAge = maxAge
+	}
+
+	return m
+}
+
+// ttlFromFeed extracts an RSS <ttl> element (the channel's suggested
+// refresh interval, in minutes), when data is an RSS feed that carries one.
+// Atom and JSON Feed don't have an equivalent, so this only ever matches
+// RSS. <ttl> is a known RSS element, not a foreign-namespace extension, so
+// gofeed's universal parser consumes it into the RSS-typed feed rather than
+// f.Extensions; data must be re-parsed with the RSS-specific parser to
+// reach it.
+func ttlFromFeed(data []byte, f *gofeed.Feed) (time.Duration, bool) {
+	if f == nil || f.FeedType != "rss" {
+		return 0, false
+	}
+
+	rssFeed, err := (&rss.Parser{}).Parse(bytes.NewReader(data))
+	if err != nil || rssFeed.TTL == "" {
+		return 0, false
+	}
+
+	minutes, err := strconv.Atoi(strings.TrimSpace(rssFeed.TTL))
+	if err != nil || minutes <= 0 {
+		return 0, false
+	}
+	return time.Duration(minutes) * time.Minute, true
+}
+
+// parseMaxAge extracts the max-age directive from a Cache-Control header
+// value, if present.
+func parseMaxAge(cacheControl string) (time.Duration, bool) {
+	for _, directive := range strings.Split(cacheControl, ",") {
+		directive = strings.TrimSpace(directive)
+		name, value, found := strings.Cut(directive, "=")
+		if !found || strings.ToLower(strings.TrimSpace(name)) != "max-age" {
+			continue
+		}
+		seconds, err := strconv.Atoi(strings.TrimSpace(value))
+		if err != nil {
+			continue
+		}
+		return time.Duration(seconds) * time.Second, true
+	}
+	return 0, false
+}
@@ -0,0 +1,44 @@
+package feed
+
+import (
+	"bytes"
+	"os"
+	"testing"
+	"time"
+)
+
+func TestTTLFromFeed(t *testing.T) {
+	data, err := os.ReadFile("testdata/ttl.rss.xml")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	f, err := parseFeed(bytes.NewReader(data))
+	if err != nil {
+		t.Fatalf("parseFeed: %v", err)
+	}
+
+	ttl, ok := ttlFromFeed(data, f)
+	if !ok {
+		t.Fatal("ttlFromFeed: expected a ttl to be found")
+	}
+	if want := 120 * time.Minute; ttl != want {
+		t.Errorf("ttl = %v, want %v", ttl, want)
+	}
+}
+
+func TestTTLFromFeedMissing(t *testing.T) {
+	data, err := os.ReadFile("testdata/windsor.rss.xml")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	f, err := parseFeed(bytes.NewReader(data))
+	if err != nil {
+		t.Fatalf("parseFeed: %v", err)
+	}
+
+	if _, ok := ttlFromFeed(data, f); ok {
+		t.Error("ttlFromFeed: expected no ttl to be found")
+	}
+}
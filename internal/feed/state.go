@@ -0,0 +1,143 @@
+package feed
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/mail"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/mmcdole/gofeed"
+)
+
+// fallbackDateLayouts are tried, in order, for a PubDate string that
+// net/mail.ParseDate can't handle — malformed feeds rarely stick to
+// RFC 1123Z.
+var fallbackDateLayouts = []string{
+	time.RFC1123Z,
+	time.RFC1123,
+	time.RFC822Z,
+	time.RFC822,
+	time.RFC3339,
+	"2006-01-02 15:04:05",
+	"2006-01-02",
+}
+
+// itemState is what we persist per item between runs: enough to tell
+// whether it has changed even when a feed's PubDate is missing or
+// reused across edits.
+type itemState struct {
+	Fingerprint string    `json:"fingerprint"`
+	PubDate     time.Time `json:"pub_date,omitempty"`
+}
+
+// State is the full set of item fingerprints known for a feed, keyed by
+// itemKey.
+type State struct {
+	Items map[string]itemState `json:"items"`
+}
+
+// statePath returns the sidecar path used to persist item fingerprints for
+// the feed cached at path.
+func statePath(path string) string {
+	return path + ".state.json"
+}
+
+// loadState reads the fingerprint state for path, returning an empty State
+// if none has been recorded yet.
+func loadState(path string) (State, error) {
+	b, err := os.ReadFile(statePath(path))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return State{Items: make(map[string]itemState)}, nil
+		}
+		return State{}, err
+	}
+
+	var s State
+	if err := json.Unmarshal(b, &s); err != nil {
+		return State{}, err
+	}
+	if s.Items == nil {
+		s.Items = make(map[string]itemState)
+	}
+	return s, nil
+}
+
+// saveState persists s as the fingerprint state for path.
+func saveState(path string, s State) error {
+	b, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(statePath(path), b, 0o644)
+}
+
+// itemKey returns the identity an item's state is tracked under: its GUID
+// when present, else its Link, else its Title.
+func itemKey(item *gofeed.Item) string {
+	if item.GUID != "" {
+		return item.GUID
+	}
+	if item.Link != "" {
+		return item.Link
+	}
+	return item.Title
+}
+
+// fingerprint computes a stable content hash for item, covering the fields
+// a feed would change if the item itself changed. Content is preferred
+// over Description when a feed populates both (Atom's <content> is the
+// full body; <summary>/Description is often just an excerpt of it).
+func fingerprint(item *gofeed.Item) string {
+	body := item.Content
+	if body == "" {
+		body = item.Description
+	}
+
+	h := sha256.New()
+	h.Write([]byte(normalize(item.Title)))
+	h.Write([]byte{0})
+	h.Write([]byte(normalize(item.Link)))
+	h.Write([]byte{0})
+	h.Write([]byte(normalize(body)))
+	for _, enc := range item.Enclosures {
+		h.Write([]byte{0})
+		h.Write([]byte(normalize(enc.URL)))
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+func normalize(s string) string {
+	return strings.TrimSpace(s)
+}
+
+// resolvePubDate returns item's publish date, guarding against a nil
+// PublishedParsed/UpdatedParsed (common in malformed feeds) by falling
+// back to parsing the raw Published/Updated strings. The bool result
+// reports whether a date could be determined at all.
+func resolvePubDate(item *gofeed.Item) (time.Time, bool) {
+	if item.PublishedParsed != nil {
+		return *item.PublishedParsed, true
+	}
+	if item.UpdatedParsed != nil {
+		return *item.UpdatedParsed, true
+	}
+
+	for _, raw := range []string{item.Published, item.Updated} {
+		if raw == "" {
+			continue
+		}
+		if t, err := mail.ParseDate(raw); err == nil {
+			return t, true
+		}
+		for _, layout := range fallbackDateLayouts {
+			if t, err := time.Parse(layout, raw); err == nil {
+				return t, true
+			}
+		}
+	}
+	return time.Time{}, false
+}
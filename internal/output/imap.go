@@ -0,0 +1,164 @@
+package output
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"net/textproto"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/emersion/go-imap"
+	"github.com/emersion/go-imap/client"
+	"github.com/mmcdole/gofeed"
+)
+
+// imgSrcRe finds <img src="..."> references in an item's HTML description
+// so they can be inlined rather than left as dangling remote links.
+var imgSrcRe = regexp.MustCompile(`(?i)<img[^>]+src="([^"]+)"`)
+
+// IMAP renders each updated item as a MIME email and appends it to a
+// folder on an IMAP server, the way a mail-based feed reader would file
+// incoming messages.
+type IMAP struct {
+	Addr     string
+	Username string
+	Password string
+	Mailbox  string
+	TLS      bool
+
+	// Client fetches images referenced in item descriptions so they can be
+	// embedded inline instead of loaded from the feed's origin on read.
+	Client *http.Client
+}
+
+// NewIMAP returns an IMAP outputter appending to mailbox on addr, using
+// client to fetch any inline images. client defaults to
+// http.DefaultClient if nil.
+func NewIMAP(addr, username, password, mailbox string, useTLS bool, client *http.Client) *IMAP {
+	if client == nil {
+		client = http.DefaultClient
+	}
+	return &IMAP{
+		Addr:     addr,
+		Username: username,
+		Password: password,
+		Mailbox:  mailbox,
+		TLS:      useTLS,
+		Client:   client,
+	}
+}
+
+// Publish appends each item in items to the configured mailbox.
+func (i *IMAP) Publish(ctx context.Context, feedName string, items []*gofeed.Item) error {
+	if len(items) == 0 {
+		return nil
+	}
+
+	c, err := i.dial()
+	if err != nil {
+		return fmt.Errorf("imap: unable to connect to %s: %v", i.Addr, err)
+	}
+	defer c.Logout()
+
+	if err := c.Login(i.Username, i.Password); err != nil {
+		return fmt.Errorf("imap: unable to login: %v", err)
+	}
+
+	for _, item := range items {
+		msg := i.renderMessage(ctx, feedName, item)
+		flags := []string{imap.SeenFlag}
+		if err := c.Append(i.Mailbox, flags, time.Now(), bytes.NewReader(msg)); err != nil {
+			return fmt.Errorf("imap: unable to append %q: %v", item.Title, err)
+		}
+	}
+	return nil
+}
+
+func (i *IMAP) dial() (*client.Client, error) {
+	if i.TLS {
+		return client.DialTLS(i.Addr, nil)
+	}
+	return client.Dial(i.Addr)
+}
+
+// renderMessage builds a multipart/related MIME message for item, with any
+// images referenced in its description fetched via i.Client and embedded
+// as inline parts instead of left as remote links.
+func (i *IMAP) renderMessage(ctx context.Context, feedName string, item *gofeed.Item) []byte {
+	var buf bytes.Buffer
+	w := multipart.NewWriter(&buf)
+
+	fmt.Fprintf(&buf, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", fmt.Sprintf("[%s] %s", feedName, item.Title)))
+	buf.WriteString("MIME-Version: 1.0\r\n")
+	fmt.Fprintf(&buf, "Content-Type: multipart/related; boundary=%q\r\n\r\n", w.Boundary())
+
+	body := item.Description
+	urls := imgSrcRe.FindAllStringSubmatch(body, -1)
+
+	htmlPart, _ := w.CreatePart(textproto.MIMEHeader{
+		"Content-Type": {"text/html; charset=utf-8"},
+	})
+
+	for idx, match := range urls {
+		cid := fmt.Sprintf("feed-image-%d", idx)
+		body = strings.ReplaceAll(body, match[1], "cid:"+cid)
+	}
+	io.WriteString(htmlPart, body)
+
+	for idx, match := range urls {
+		i.attachImage(ctx, w, fmt.Sprintf("feed-image-%d", idx), match[1])
+	}
+
+	w.Close()
+	return buf.Bytes()
+}
+
+// attachImage fetches url and writes it into w as an inline part with the
+// given content-id. Fetch failures are not fatal to the message: the
+// image is simply left out, since the feed item itself still matters more
+// than its illustrations.
+func (i *IMAP) attachImage(ctx context.Context, w *multipart.Writer, cid, url string) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return
+	}
+	resp, err := i.Client.Do(req)
+	if err != nil {
+		return
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return
+	}
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return
+	}
+
+	contentType := resp.Header.Get("Content-Type")
+	if contentType == "" {
+		contentType = "application/octet-stream"
+	}
+
+	part, err := w.CreatePart(textproto.MIMEHeader{
+		"Content-Type":              {contentType},
+		"Content-Transfer-Encoding": {"base64"},
+		"Content-ID":                {"<" + cid + ">"},
+		"Content-Disposition":       {"inline"},
+	})
+	if err != nil {
+		return
+	}
+
+	enc := base64.NewEncoder(base64.StdEncoding, part)
+	enc.Write(data)
+	enc.Close()
+}
@@ -0,0 +1,86 @@
+// Package output publishes feed updates to one or more destinations:
+// stdout, a webhook, SMTP, or an IMAP mailbox.
+package output
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/http"
+	"sync"
+
+	"github.com/mmcdole/gofeed"
+)
+
+// Outputter publishes a feed's new or changed items somewhere.
+// Implementations should treat items as read-only and must be safe to call
+// concurrently from different feeds.
+type Outputter interface {
+	Publish(ctx context.Context, feedName string, items []*gofeed.Item) error
+}
+
+// Config declares one configured output sink for a feed. Type selects
+// which fields apply; unused fields for a given type are ignored.
+type Config struct {
+	Type string `yaml:"type"`
+
+	// webhook
+	URL string `yaml:"url,omitempty"`
+
+	// smtp
+	Addr string   `yaml:"addr,omitempty"`
+	From string   `yaml:"from,omitempty"`
+	To   []string `yaml:"to,omitempty"`
+
+	// imap (also uses Addr above for host:port)
+	Username string `yaml:"username,omitempty"`
+	Password string `yaml:"password,omitempty"`
+	Mailbox  string `yaml:"mailbox,omitempty"`
+	TLS      bool   `yaml:"tls,omitempty"`
+}
+
+// Build constructs the Outputter described by cfg. client is shared with
+// the feed's fetch client and reused for any HTTP calls the sink itself
+// needs to make (webhook POSTs, inline image fetches for IMAP).
+func Build(cfg Config, client *http.Client) (Outputter, error) {
+	switch cfg.Type {
+	case "", "stdout":
+		return NewStdout(nil), nil
+	case "webhook":
+		return NewWebhook(cfg.URL, client), nil
+	case "smtp":
+		return NewSMTP(cfg.Addr, cfg.From, cfg.To), nil
+	case "imap":
+		return NewIMAP(cfg.Addr, cfg.Username, cfg.Password, cfg.Mailbox, cfg.TLS, client), nil
+	default:
+		return nil, fmt.Errorf("unknown output type %q", cfg.Type)
+	}
+}
+
+// PublishAll fans items out to every sink in configs, building each with
+// client and publishing to it concurrently. configs defaults to a single
+// stdout sink when empty. A sink that fails to build or publish is logged
+// and does not prevent its siblings from running.
+func PublishAll(ctx context.Context, client *http.Client, feedName string, configs []Config, items []*gofeed.Item) {
+	if len(configs) == 0 {
+		configs = []Config{{Type: "stdout"}}
+	}
+
+	var wg sync.WaitGroup
+	for _, cfg := range configs {
+		out, err := Build(cfg, client)
+		if err != nil {
+			log.Printf("%s: %v", feedName, err)
+			continue
+		}
+
+		wg.Add(1)
+		go func(out Outputter) {
+			defer wg.Done()
+			if err := out.Publish(ctx, feedName, items); err != nil {
+				log.Printf("%s: %v", feedName, err)
+			}
+		}(out)
+	}
+	wg.Wait()
+}
@@ -0,0 +1,56 @@
+package output
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/smtp"
+
+	"github.com/mmcdole/gofeed"
+)
+
+// SMTP emails each updated item individually through a mail relay at Addr.
+type SMTP struct {
+	Addr string
+	From string
+	To   []string
+}
+
+// NewSMTP returns an SMTP outputter relaying through addr.
+func NewSMTP(addr, from string, to []string) *SMTP {
+	return &SMTP{Addr: addr, From: from, To: to}
+}
+
+// Publish sends one email per item in items.
+func (s *SMTP) Publish(_ context.Context, feedName string, items []*gofeed.Item) error {
+	for _, item := range items {
+		msg := buildMessage(s.From, s.To, feedName, item)
+		if err := smtp.SendMail(s.Addr, nil, s.From, s.To, msg); err != nil {
+			return fmt.Errorf("smtp: unable to send %q: %v", item.Title, err)
+		}
+	}
+	return nil
+}
+
+// buildMessage renders a minimal RFC 5322 message for item.
+func buildMessage(from string, to []string, feedName string, item *gofeed.Item) []byte {
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "From: %s\r\n", from)
+	fmt.Fprintf(&buf, "To: %s\r\n", joinAddrs(to))
+	fmt.Fprintf(&buf, "Subject: [%s] %s\r\n", feedName, item.Title)
+	buf.WriteString("MIME-Version: 1.0\r\n")
+	buf.WriteString("Content-Type: text/html; charset=utf-8\r\n\r\n")
+	buf.WriteString(item.Description)
+	return buf.Bytes()
+}
+
+func joinAddrs(addrs []string) string {
+	out := ""
+	for i, a := range addrs {
+		if i > 0 {
+			out += ", "
+		}
+		out += a
+	}
+	return out
+}
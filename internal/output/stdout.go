@@ -0,0 +1,42 @@
+package output
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"os"
+
+	"github.com/mmcdole/gofeed"
+)
+
+// Stdout writes a feed's updated items as indented JSON to Writer.
+type Stdout struct {
+	Writer io.Writer
+}
+
+// NewStdout returns a Stdout outputter writing to w, or os.Stdout if w is
+// nil.
+func NewStdout(w io.Writer) *Stdout {
+	if w == nil {
+		w = os.Stdout
+	}
+	return &Stdout{Writer: w}
+}
+
+// Publish writes items as a standalone JSON document keyed by feedName. It
+// is a no-op when items is empty. Each call writes its own document; feeds
+// are not aggregated into one document across a run, since each feed fans
+// out to its own configured sinks (see PublishAll) and --daemon mode polls
+// feeds independently and indefinitely rather than in lockstep batches.
+func (s *Stdout) Publish(_ context.Context, feedName string, items []*gofeed.Item) error {
+	if len(items) == 0 {
+		return nil
+	}
+
+	b, err := json.MarshalIndent(map[string][]*gofeed.Item{feedName: items}, "", "  ")
+	if err != nil {
+		return err
+	}
+	_, err = s.Writer.Write(append(b, '\n'))
+	return err
+}
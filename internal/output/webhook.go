@@ -0,0 +1,61 @@
+package output
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/mmcdole/gofeed"
+)
+
+// Webhook POSTs a feed's updated items as a single JSON document to URL.
+type Webhook struct {
+	URL    string
+	Client *http.Client
+}
+
+// NewWebhook returns a Webhook outputter posting to url with client, or
+// http.DefaultClient if client is nil.
+func NewWebhook(url string, client *http.Client) *Webhook {
+	if client == nil {
+		client = http.DefaultClient
+	}
+	return &Webhook{URL: url, Client: client}
+}
+
+type webhookPayload struct {
+	Feed  string         `json:"feed"`
+	Items []*gofeed.Item `json:"items"`
+}
+
+// Publish POSTs items to the webhook URL. It is a no-op when items is
+// empty.
+func (w *Webhook) Publish(ctx context.Context, feedName string, items []*gofeed.Item) error {
+	if len(items) == 0 {
+		return nil
+	}
+
+	b, err := json.Marshal(webhookPayload{Feed: feedName, Items: items})
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, w.URL, bytes.NewReader(b))
+	if err != nil {
+		return fmt.Errorf("webhook: unable to build request: %v", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := w.Client.Do(req)
+	if err != nil {
+		return fmt.Errorf("webhook: unable to post to %s: %v", w.URL, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook: %s returned %s", w.URL, resp.Status)
+	}
+	return nil
+}
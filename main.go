@@ -1,194 +1,121 @@
 package main
 
 import (
-	"bytes"
-	"crypto/tls"
-	"crypto/x509"
-	_ "embed"
-	"encoding/json"
+	"context"
 	"flag"
-	"fmt"
-	"io"
 	"log"
-	"net/http"
-	"os"
+	"os/signal"
+	"sync"
+	"syscall"
 	"time"
 
-	"github.com/mmcdole/gofeed/rss"
+	"github.com/dntiontk/rss-feed-aggregator/internal/config"
+	"github.com/dntiontk/rss-feed-aggregator/internal/daemon"
+	"github.com/dntiontk/rss-feed-aggregator/internal/feed"
+	"github.com/dntiontk/rss-feed-aggregator/internal/output"
 )
 
 /*
-This is a simple RSS feed aggregator for the City of Windsor website
-that uses the Open Data feed to create a summary of changes. When
-invoked, the program will fetch the the remote feed, and compare
-it to a local copy. After generating and outputting the a summary
-of changes, the remote copy will overwrite the local copy
-(create if it doesn't exist). The invoker is responsible for keeping
- the local copy up-to-date.
+This is an RSS feed aggregator that, for every feed declared in its config
+file, fetches the remote copy and compares it to a local cache, then
+publishes whatever is new or changed to that feed's configured outputs
+(stdout by default). The remote copy overwrites the local copy (created if
+it doesn't exist). Each feed publishes independently to its own sinks as
+soon as its own fetch completes; updates are not batched into one
+aggregated document across feeds, since --daemon mode polls each feed on
+its own schedule rather than in lockstep.
+
+By default it does one pass over every feed and exits, meant to be driven
+by cron. Pass --daemon to keep it running instead, polling each feed on
+its own schedule until it receives SIGINT/SIGTERM.
 */
 
-//go:embed star.citywindsor.ca
-var cert []byte
-
 var (
-	pathFlag string
-	urlFlag  string
+	configFlag      string
+	minRefreshFlag  time.Duration
+	daemonFlag      bool
+	metricsAddrFlag string
 )
 
 func main() {
-	flag.StringVar(&pathFlag, "path", "./feeds/opendata.xml", "path to local xml file to diff")
-	flag.StringVar(&urlFlag, "url", "https://opendata.citywindsor.ca/RSS", "RSS feed url")
+	flag.StringVar(&configFlag, "config", "./feeds.yaml", "path to feed config file")
+	flag.DurationVar(&minRefreshFlag, "min-refresh", 0, "skip fetching a feed if its cache is younger than this")
+	flag.BoolVar(&daemonFlag, "daemon", false, "keep running, polling each feed on its own schedule, instead of exiting after one pass")
+	flag.StringVar(&metricsAddrFlag, "metrics-addr", ":9090", "address to serve /metrics on in --daemon mode")
 	flag.Parse()
-	/*
-		Note that we need to add the ca-cert for "citywindsor.ca" to
-		to our HTTP client in order to access the data programatically
-	*/
-	client, err := newClientWithCA(cert)
-	if err != nil {
-		log.Fatal(err)
-	}
 
-	// Get our Open Data update list
-	opendataUpdates, err := getFeedUpdates(client, pathFlag, urlFlag)
+	cfg, err := config.Load(configFlag)
 	if err != nil {
 		log.Fatal(err)
 	}
 
-	// exit if no changes found
-	if len(opendataUpdates) == 0 {
-		log.Printf("no changes found")
-	} else {
-		b, err := json.MarshalIndent(opendataUpdates, "", "  ")
-		if err != nil {
-			log.Fatal(err)
-		}
+	pool := feed.NewClientPool()
 
-		log.Printf("%s", b)
+	if daemonFlag {
+		runDaemon(pool, cfg.Feeds)
+		return
 	}
+
+	run(context.Background(), pool, cfg.Feeds, minRefreshFlag)
 }
 
-func getFeedUpdates(client *http.Client, path, url string) ([]*rss.Item, error) {
-	localFeed, err := parseLocalFeed(path)
-	if err != nil {
-		return nil, err
-	}
+// run fetches every feed in feeds concurrently and publishes each one's
+// updated items to its configured outputs as soon as its own fetch
+// completes, independently of its siblings. A feed that fails to fetch, or
+// an output that fails to publish, is logged and otherwise isolated from
+// its siblings.
+func run(ctx context.Context, pool *feed.ClientPool, feeds []config.Feed, minRefresh time.Duration) {
+	var wg sync.WaitGroup
 
-	/*
-		Let's create a map[string]time.Time to quickly lookup items and
-		compare dates
-	*/
-	itemMap := make(map[string]time.Time)
-
-	for _, item := range localFeed.Items {
-		formatted := item.PubDateParsed.Format(time.RFC3339)
-		pubDate, err := time.Parse(time.RFC3339, formatted)
-		if err != nil {
-			return nil, fmt.Errorf("unable to parse date from local feed: %v", err)
-		}
-		itemMap[item.Title] = pubDate
-	}
+	for _, f := range feeds {
+		wg.Add(1)
+		go func(f config.Feed) {
+			defer wg.Done()
 
-	// Parse the remote copy of the opendata feed
-	remoteFeed, err := parseRemoteFeed(client, path, url)
-	if err != nil {
-		return nil, fmt.Errorf("unable to parse remote feed: %v", err)
-	}
+			client, err := pool.Get(f.Feed)
+			if err != nil {
+				log.Printf("%s: %v", f.Name, err)
+				return
+			}
 
-	// Make updatedItems lists
-	return lookupUpdates(itemMap, remoteFeed.Items)
-}
+			fetchCtx, cancel := feed.FetchContext(ctx, f.Timeout)
+			defer cancel()
 
-func lookupUpdates(m map[string]time.Time, items []*rss.Item) ([]*rss.Item, error) {
-	updatedItems := make([]*rss.Item, 0)
-	for _, i := range items {
-		if date, ok := m[i.Title]; ok {
-			formatted := i.PubDateParsed.Format(time.RFC3339)
-			rDate, err := time.Parse(time.RFC3339, formatted)
+			items, err := feed.GetUpdates(fetchCtx, client, f.Feed, minRefresh)
 			if err != nil {
-				return nil, err
+				log.Printf("%s: %v", f.Name, err)
+				return
 			}
-			if !rDate.Equal(date) {
-				updatedItems = append(updatedItems, i)
+			if len(items) == 0 {
+				log.Printf("%s: no changes found", f.Name)
+				return
 			}
-		} else {
-			updatedItems = append(updatedItems, i)
-		}
-	}
-	return updatedItems, nil
-}
 
-// newClientWithCA reads a CA cert as bytes and returns an HTTP client with the appropriate cert pool
-func newClientWithCA(cert []byte) (*http.Client, error) {
-	pool := x509.NewCertPool()
-	if ok := pool.AppendCertsFromPEM(cert); !ok {
-		return nil, fmt.Errorf("unable to append ca to cert pool")
+			output.PublishAll(ctx, client, f.Name, f.Outputs, items)
+		}(f)
 	}
 
-	return &http.Client{
-		Transport: &http.Transport{
-			TLSClientConfig: &tls.Config{
-				RootCAs: pool,
-			},
-		},
-	}, nil
+	wg.Wait()
 }
 
-func parseRemoteFeed(c *http.Client, path, url string) (*rss.Feed, error) {
-	resp, err := c.Get(url)
-	if err != nil {
-		return nil, fmt.Errorf("unable to get remote feed: %v", err)
-	}
-	defer resp.Body.Close()
-
-	data, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return nil, err
-	}
-	if err := write(data, path); err != nil {
-		return nil, err
-	}
+// runDaemon keeps the process alive, polling every feed in feeds on its
+// own schedule and serving /metrics, until SIGINT or SIGTERM is received.
+func runDaemon(pool *feed.ClientPool, feeds []config.Feed) {
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
 
-	feed, err := parseRSSFeed(bytes.NewBuffer(data))
-	if err != nil {
-		return nil, fmt.Errorf("unable to parse remote feed: %v", err)
-	}
-	return feed, nil
-}
+	d := daemon.New(pool, feeds, minRefreshFlag)
 
-func parseLocalFeed(path string) (*rss.Feed, error) {
-	b, err := os.ReadFile(path)
-	if err != nil {
-		if os.IsNotExist(err) {
-			return &rss.Feed{}, nil
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		if err := d.ServeMetrics(ctx, metricsAddrFlag); err != nil {
+			log.Printf("metrics server: %v", err)
 		}
-		return &rss.Feed{}, fmt.Errorf("unable to read local feed: %v", err)
-	}
-
-	feed, err := parseRSSFeed(bytes.NewBuffer(b))
-	if err != nil {
-		return &rss.Feed{}, fmt.Errorf("unable to parse local feed: %v", err)
-	}
+	}()
 
-	return feed, nil
-}
-
-func write(b []byte, path string) error {
-	f, err := os.Create(path)
-	if err != nil {
-		return err
-	}
-	if _, err := f.Write(b); err != nil {
-		return err
-	}
-	return nil
-}
-
-func parseRSSFeed(r io.Reader) (*rss.Feed, error) {
-	fp := rss.Parser{}
-
-	feed, err := fp.Parse(r)
-	if err != nil {
-		return nil, err
-	}
-	return feed, nil
+	log.Printf("daemon: polling %d feed(s), metrics on %s/metrics", len(feeds), metricsAddrFlag)
+	d.Run(ctx)
+	wg.Wait()
 }